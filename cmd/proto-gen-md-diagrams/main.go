@@ -0,0 +1,60 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command proto-gen-md-diagrams parses one or more .proto files and renders
+// the result as Markdown (with embedded Mermaid diagrams), JSON, or YAML.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mosherBT/proto-gen-md-diagrams/pkg/proto"
+)
+
+func main() {
+	format := flag.String("format", "md", "output format: md, json, or yaml")
+	flag.Parse()
+
+	out, err := proto.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	services, messages, err := proto.ParseFiles(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var rendered []byte
+	switch out {
+	case proto.FormatJSON:
+		rendered, err = proto.MarshalTreeJSON(services, messages)
+	case proto.FormatYAML:
+		rendered, err = proto.MarshalTreeYAML(services, messages)
+	default:
+		rendered, err = proto.RenderMarkdown(services, messages)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Stdout.Write(rendered)
+}
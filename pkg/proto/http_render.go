@@ -0,0 +1,179 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StreamingMode classifies an Rpc by which side(s) of the call stream.
+type StreamingMode string
+
+const (
+	Unary           StreamingMode = "unary"
+	ServerStreaming StreamingMode = "server-stream"
+	ClientStreaming StreamingMode = "client-stream"
+	BidiStreaming   StreamingMode = "bidi"
+)
+
+func rpcStreamingMode(r *Rpc) StreamingMode {
+	in := len(r.InputParameters) > 0 && r.InputParameters[0].Streaming
+	out := len(r.ReturnParameters) > 0 && r.ReturnParameters[0].Streaming
+	switch {
+	case in && out:
+		return BidiStreaming
+	case out:
+		return ServerStreaming
+	case in:
+		return ClientStreaming
+	default:
+		return Unary
+	}
+}
+
+// RpcHttpBinding pairs an Rpc with the HttpRule(s) declared on it via a
+// google.api.http option, including any additional_bindings.
+type RpcHttpBinding struct {
+	Rpc   *Rpc
+	Rules []*HttpRule
+}
+
+// ServiceHttpBindings collects the HTTP bindings declared on each Rpc of a
+// Service, in declaration order. RPCs without a google.api.http option are
+// omitted.
+func ServiceHttpBindings(s *Service) []RpcHttpBinding {
+	var bindings []RpcHttpBinding
+	for _, r := range s.Rpcs {
+		var rules []*HttpRule
+		for _, o := range r.Options {
+			rule, ok := ParseHttpRule(o)
+			if !ok {
+				continue
+			}
+			rules = append(rules, rule)
+			rules = append(rules, rule.AdditionalBindings...)
+		}
+		if len(rules) > 0 {
+			bindings = append(bindings, RpcHttpBinding{Rpc: r, Rules: rules})
+		}
+	}
+	return bindings
+}
+
+// RenderHttpTable renders the `Method | HTTP | Path | Body | Response` table
+// for every RPC in the service that carries a google.api.http option. It
+// returns an empty string if the service has none.
+func RenderHttpTable(s *Service) string {
+	bindings := ServiceHttpBindings(s)
+	if len(bindings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("| Method | HTTP | Path | Body | Response |\n")
+	b.WriteString("|--------|------|------|------|----------|\n")
+	for _, binding := range bindings {
+		response := "-"
+		if len(binding.Rpc.ReturnParameters) > 0 {
+			response = binding.Rpc.ReturnParameters[0].Type
+		}
+		for _, rule := range binding.Rules {
+			body := rule.Body
+			if body == "" {
+				body = "-"
+			}
+			fmt.Fprintf(&b, "| %s | %s | `%s` | %s | %s |\n",
+				binding.Rpc.Name, rule.Verb, rule.Path, body, response)
+		}
+	}
+	return b.String()
+}
+
+// RenderSequenceDiagram renders a Mermaid sequence diagram for one HTTP
+// binding, showing the client -> gateway -> server hops appropriate to the
+// RPC's streaming mode.
+func RenderSequenceDiagram(rpc *Rpc, rule *HttpRule) string {
+	var b strings.Builder
+	b.WriteString("```mermaid\n")
+	b.WriteString("sequenceDiagram\n")
+	b.WriteString("    participant Client\n")
+	b.WriteString("    participant Gateway\n")
+	b.WriteString("    participant Server\n")
+	fmt.Fprintf(&b, "    Client->>Gateway: %s %s\n", rule.Verb, rule.Path)
+	fmt.Fprintf(&b, "    Gateway->>Server: %s\n", rpc.Name)
+
+	switch rpcStreamingMode(rpc) {
+	case ServerStreaming:
+		b.WriteString("    loop stream\n")
+		b.WriteString("        Server-->>Gateway: response chunk\n")
+		b.WriteString("        Gateway-->>Client: response chunk\n")
+		b.WriteString("    end\n")
+	case ClientStreaming:
+		b.WriteString("    loop stream\n")
+		b.WriteString("        Client->>Gateway: request chunk\n")
+		b.WriteString("        Gateway->>Server: request chunk\n")
+		b.WriteString("    end\n")
+		b.WriteString("    Server-->>Gateway: response\n")
+		b.WriteString("    Gateway-->>Client: response\n")
+	case BidiStreaming:
+		b.WriteString("    par requests\n")
+		b.WriteString("        Client->>Gateway: request chunk\n")
+		b.WriteString("        Gateway->>Server: request chunk\n")
+		b.WriteString("    and responses\n")
+		b.WriteString("        Server-->>Gateway: response chunk\n")
+		b.WriteString("        Gateway-->>Client: response chunk\n")
+		b.WriteString("    end\n")
+	default: // Unary
+		b.WriteString("    Server-->>Gateway: response\n")
+		b.WriteString("    Gateway-->>Client: response\n")
+	}
+
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// RenderServiceSequenceDiagrams renders one sequence diagram per HTTP
+// binding declared on the service's RPCs.
+func RenderServiceSequenceDiagrams(s *Service) string {
+	var b strings.Builder
+	for _, binding := range ServiceHttpBindings(s) {
+		for _, rule := range binding.Rules {
+			b.WriteString(RenderSequenceDiagram(binding.Rpc, rule))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// RenderServiceMarkdown renders a service's HTTP route table followed by its
+// per-RPC sequence diagrams as one Markdown section. It returns an empty
+// string for services with no google.api.http bindings, so callers can
+// append the result unconditionally.
+func RenderServiceMarkdown(s *Service) string {
+	table := RenderHttpTable(s)
+	if table == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", s.Name)
+	b.WriteString(table)
+	b.WriteString("\n")
+	b.WriteString(RenderServiceSequenceDiagrams(s))
+	return b.String()
+}
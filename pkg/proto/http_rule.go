@@ -0,0 +1,114 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"regexp"
+	"strings"
+)
+
+var pathVariablePattern = regexp.MustCompile(`\{(\w+)=([^}]*)\}`)
+
+// httpVerbFields are the google.api.http message keys that name an HTTP
+// verb; their value is the path template.
+var httpVerbFields = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "patch": true,
+}
+
+// PathVariable is one `{name=pattern}` segment of an HTTP path template, as
+// used by google.api.http, e.g. `{name=projects/*/instances/*}`.
+type PathVariable struct {
+	Name    string
+	Pattern string
+}
+
+// HttpRule is the parsed form of a google.api.http option: the HTTP verb,
+// path template, optional body field, and any additional_bindings.
+type HttpRule struct {
+	Verb               string
+	Path               string
+	PathVariables      []PathVariable
+	Body               string
+	AdditionalBindings []*HttpRule
+}
+
+// ParseHttpRule recognizes a google.api.http option and parses its
+// structured Value into an HttpRule. It reports false for any other option.
+func ParseHttpRule(o *RpcOption) (*HttpRule, bool) {
+	if o.Name != "google.api.http" {
+		return nil, false
+	}
+	rule := httpRuleFromValue(o.Value)
+	if rule == nil {
+		return nil, false
+	}
+	return rule, true
+}
+
+// ParseMethodSignature recognizes a google.api.method_signature option and
+// returns its comma-separated field list.
+func ParseMethodSignature(o *RpcOption) ([]string, bool) {
+	if o.Name != "google.api.method_signature" || o.Value.Kind != ScalarString {
+		return nil, false
+	}
+	var fields []string
+	for _, f := range strings.Split(o.Value.Scalar, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields, true
+}
+
+// httpRuleFromValue walks a `{ get: "..." body: "..." additional_bindings {
+// ... } }` message literal and builds the HttpRule it describes, recursing
+// into every additional_bindings entry.
+func httpRuleFromValue(v OptionValue) *HttpRule {
+	if v.Kind != MessageValue {
+		return nil
+	}
+
+	var rule HttpRule
+	sawVerb := false
+	for _, f := range v.Fields {
+		switch {
+		case httpVerbFields[f.Key] && f.Value.Kind == ScalarString:
+			rule.Verb = strings.ToUpper(f.Key)
+			rule.Path = f.Value.Scalar
+			rule.PathVariables = parsePathVariables(f.Value.Scalar)
+			sawVerb = true
+		case f.Key == "body" && f.Value.Kind == ScalarString:
+			rule.Body = f.Value.Scalar
+		case f.Key == "additional_bindings":
+			if sub := httpRuleFromValue(f.Value); sub != nil {
+				rule.AdditionalBindings = append(rule.AdditionalBindings, sub)
+			}
+		}
+	}
+	if !sawVerb {
+		return nil
+	}
+	return &rule
+}
+
+func parsePathVariables(path string) []PathVariable {
+	var vars []PathVariable
+	for _, m := range pathVariablePattern.FindAllStringSubmatch(path, -1) {
+		vars = append(vars, PathVariable{Name: m[1], Pattern: m[2]})
+	}
+	return vars
+}
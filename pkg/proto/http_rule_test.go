@@ -0,0 +1,149 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHttpRuleMultipleAdditionalBindings(t *testing.T) {
+	opt := &RpcOption{
+		Name: "google.api.http",
+		Value: ParseOptionValue(`{
+			get: "/v1/{name=projects/*}"
+			additional_bindings {
+				post: "/v1/{parent=projects/*}/instances"
+				body: "instance"
+			}
+			additional_bindings {
+				post: "/v1/{parent=projects/*}/instances:create"
+				body: "*"
+			}
+		}`),
+	}
+
+	rule, ok := ParseHttpRule(opt)
+	if !ok {
+		t.Fatal("ParseHttpRule returned ok=false")
+	}
+	if rule.Verb != "GET" || rule.Path != "/v1/{name=projects/*}" {
+		t.Errorf("unexpected primary rule: %+v", rule)
+	}
+	if len(rule.PathVariables) != 1 || rule.PathVariables[0].Name != "name" {
+		t.Errorf("unexpected path variables: %+v", rule.PathVariables)
+	}
+	if len(rule.AdditionalBindings) != 2 {
+		t.Fatalf("got %d additional bindings, want 2", len(rule.AdditionalBindings))
+	}
+	if rule.AdditionalBindings[0].Verb != "POST" || rule.AdditionalBindings[0].Body != "instance" {
+		t.Errorf("unexpected first binding: %+v", rule.AdditionalBindings[0])
+	}
+	if rule.AdditionalBindings[1].Verb != "POST" || rule.AdditionalBindings[1].Body != "*" {
+		t.Errorf("unexpected second binding: %+v", rule.AdditionalBindings[1])
+	}
+}
+
+func TestParseHttpRuleIgnoresOtherOptions(t *testing.T) {
+	opt := &RpcOption{Name: "google.api.method_signature", Value: ParseOptionValue(`"name"`)}
+	if _, ok := ParseHttpRule(opt); ok {
+		t.Error("ParseHttpRule should ignore non-http options")
+	}
+}
+
+func TestParseMethodSignature(t *testing.T) {
+	opt := &RpcOption{Name: "google.api.method_signature", Value: ParseOptionValue(`"parent,instance_id,instance"`)}
+	fields, ok := ParseMethodSignature(opt)
+	if !ok {
+		t.Fatal("ParseMethodSignature returned ok=false")
+	}
+	want := []string{"parent", "instance_id", "instance"}
+	if len(fields) != len(want) {
+		t.Fatalf("got %v, want %v", fields, want)
+	}
+	for i, f := range fields {
+		if f != want[i] {
+			t.Errorf("field %d = %q, want %q", i, f, want[i])
+		}
+	}
+}
+
+func TestRpcStreamingMode(t *testing.T) {
+	tests := []struct {
+		name string
+		rpc  *Rpc
+		want StreamingMode
+	}{
+		{"unary", &Rpc{
+			InputParameters:  []*Parameter{{Type: "Req"}},
+			ReturnParameters: []*Parameter{{Type: "Resp"}},
+		}, Unary},
+		{"server streaming", &Rpc{
+			InputParameters:  []*Parameter{{Type: "Req"}},
+			ReturnParameters: []*Parameter{{Streaming: true, Type: "Resp"}},
+		}, ServerStreaming},
+		{"client streaming", &Rpc{
+			InputParameters:  []*Parameter{{Streaming: true, Type: "Req"}},
+			ReturnParameters: []*Parameter{{Type: "Resp"}},
+		}, ClientStreaming},
+		{"bidi streaming", &Rpc{
+			InputParameters:  []*Parameter{{Streaming: true, Type: "Req"}},
+			ReturnParameters: []*Parameter{{Streaming: true, Type: "Resp"}},
+		}, BidiStreaming},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rpcStreamingMode(tt.rpc); got != tt.want {
+				t.Errorf("rpcStreamingMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderServiceMarkdownEmptyWithoutHttpBindings(t *testing.T) {
+	s := &Service{Name: "Plain", Rpcs: []*Rpc{{Name: "DoThing"}}}
+	if got := RenderServiceMarkdown(s); got != "" {
+		t.Errorf("RenderServiceMarkdown() = %q, want empty string", got)
+	}
+}
+
+func TestRenderServiceMarkdownIncludesTableAndDiagram(t *testing.T) {
+	s := &Service{
+		Name: "Greeter",
+		Rpcs: []*Rpc{
+			{
+				Name:             "SayHello",
+				InputParameters:  []*Parameter{{Type: "HelloRequest"}},
+				ReturnParameters: []*Parameter{{Type: "HelloReply"}},
+				Options: []*RpcOption{
+					{Name: "google.api.http", Value: ParseOptionValue(`{get: "/v1/hello"}`)},
+				},
+			},
+		},
+	}
+
+	got := RenderServiceMarkdown(s)
+	if got == "" {
+		t.Fatal("RenderServiceMarkdown() returned empty string, want a rendered section")
+	}
+	if !strings.Contains(got, "| Method | HTTP | Path | Body | Response |") {
+		t.Errorf("RenderServiceMarkdown() missing HTTP table:\n%s", got)
+	}
+	if !strings.Contains(got, "sequenceDiagram") {
+		t.Errorf("RenderServiceMarkdown() missing sequence diagram:\n%s", got)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParameterModel is the serializable form of a Parameter. Field order is
+// fixed so JSON/YAML output stays stable across runs and is diff-friendly.
+type ParameterModel struct {
+	Stream bool   `json:"stream,omitempty" yaml:"stream,omitempty"`
+	Type   string `json:"type" yaml:"type"`
+}
+
+// OptionValueModel is the serializable form of an OptionValue.
+type OptionValueModel struct {
+	Kind     string             `json:"kind" yaml:"kind"`
+	Scalar   string             `json:"scalar,omitempty" yaml:"scalar,omitempty"`
+	Elements []OptionValueModel `json:"elements,omitempty" yaml:"elements,omitempty"`
+	Fields   []OptionFieldModel `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// OptionFieldModel is the serializable form of an OptionField.
+type OptionFieldModel struct {
+	Key   string           `json:"key" yaml:"key"`
+	Value OptionValueModel `json:"value" yaml:"value"`
+}
+
+var optionValueKindNames = map[OptionValueKind]string{
+	ScalarString: "string",
+	ScalarNumber: "number",
+	ScalarBool:   "bool",
+	ScalarIdent:  "identifier",
+	ListValue:    "list",
+	MessageValue: "message",
+}
+
+func newOptionValueModel(v OptionValue) OptionValueModel {
+	model := OptionValueModel{Kind: optionValueKindNames[v.Kind], Scalar: v.Scalar}
+	for _, e := range v.Elements {
+		model.Elements = append(model.Elements, newOptionValueModel(e))
+	}
+	for _, f := range v.Fields {
+		model.Fields = append(model.Fields, OptionFieldModel{Key: f.Key, Value: newOptionValueModel(f.Value)})
+	}
+	return model
+}
+
+// RpcOptionModel is the serializable form of an RpcOption. Value carries the
+// structured option-body tree; Body keeps the original raw text for
+// back-compat with consumers that parsed it themselves.
+type RpcOptionModel struct {
+	Name  string           `json:"name" yaml:"name"`
+	Value OptionValueModel `json:"value" yaml:"value"`
+	Body  string           `json:"body" yaml:"body"`
+}
+
+// RpcModel is the serializable form of an Rpc.
+type RpcModel struct {
+	Name    string           `json:"name" yaml:"name"`
+	Inputs  []ParameterModel `json:"inputs" yaml:"inputs"`
+	Returns []ParameterModel `json:"returns" yaml:"returns"`
+	Options []RpcOptionModel `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ServiceModel is the serializable form of a Service.
+type ServiceModel struct {
+	Name string     `json:"name" yaml:"name"`
+	Rpcs []RpcModel `json:"rpcs" yaml:"rpcs"`
+}
+
+// MessageModel is the serializable form of a Message.
+type MessageModel struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// TreeModel is the root of the serialized parse tree: every Service and
+// Message discovered while walking the .proto files, in encounter order.
+type TreeModel struct {
+	Services []ServiceModel `json:"services" yaml:"services"`
+	Messages []MessageModel `json:"messages" yaml:"messages"`
+}
+
+func newParameterModel(p *Parameter) ParameterModel {
+	return ParameterModel{Stream: p.Streaming, Type: p.Type}
+}
+
+func newParameterModels(params []*Parameter) []ParameterModel {
+	out := make([]ParameterModel, 0, len(params))
+	for _, p := range params {
+		out = append(out, newParameterModel(p))
+	}
+	return out
+}
+
+func newRpcOptionModel(o *RpcOption) RpcOptionModel {
+	return RpcOptionModel{Name: o.Name, Value: newOptionValueModel(o.Value), Body: o.RawBody}
+}
+
+func newRpcOptionModels(options []*RpcOption) []RpcOptionModel {
+	out := make([]RpcOptionModel, 0, len(options))
+	for _, o := range options {
+		out = append(out, newRpcOptionModel(o))
+	}
+	return out
+}
+
+// NewRpcModel builds the serializable form of an Rpc.
+func NewRpcModel(r *Rpc) RpcModel {
+	return RpcModel{
+		Name:    r.Name,
+		Inputs:  newParameterModels(r.InputParameters),
+		Returns: newParameterModels(r.ReturnParameters),
+		Options: newRpcOptionModels(r.Options),
+	}
+}
+
+// NewServiceModel builds the serializable form of a Service.
+func NewServiceModel(s *Service) ServiceModel {
+	rpcs := make([]RpcModel, 0, len(s.Rpcs))
+	for _, r := range s.Rpcs {
+		rpcs = append(rpcs, NewRpcModel(r))
+	}
+	return ServiceModel{Name: s.Name, Rpcs: rpcs}
+}
+
+// NewMessageModel builds the serializable form of a Message.
+func NewMessageModel(m *Message) MessageModel {
+	return MessageModel{Name: m.Name}
+}
+
+// NewTreeModel builds the serializable form of a full parse tree.
+func NewTreeModel(services []*Service, messages []*Message) TreeModel {
+	tree := TreeModel{
+		Services: make([]ServiceModel, 0, len(services)),
+		Messages: make([]MessageModel, 0, len(messages)),
+	}
+	for _, s := range services {
+		tree.Services = append(tree.Services, NewServiceModel(s))
+	}
+	for _, m := range messages {
+		tree.Messages = append(tree.Messages, NewMessageModel(m))
+	}
+	return tree
+}
+
+// MarshalTreeJSON renders the full parse tree as indented JSON.
+func MarshalTreeJSON(services []*Service, messages []*Message) ([]byte, error) {
+	return json.MarshalIndent(NewTreeModel(services, messages), "", "  ")
+}
+
+// MarshalTreeYAML renders the full parse tree as YAML.
+func MarshalTreeYAML(services []*Service, messages []*Message) ([]byte, error) {
+	return yaml.Marshal(NewTreeModel(services, messages))
+}
+
+// MarshalServiceJSON renders a single Service as indented JSON.
+func MarshalServiceJSON(s *Service) ([]byte, error) {
+	return json.MarshalIndent(NewServiceModel(s), "", "  ")
+}
+
+// MarshalServiceYAML renders a single Service as YAML.
+func MarshalServiceYAML(s *Service) ([]byte, error) {
+	return yaml.Marshal(NewServiceModel(s))
+}
+
+// MarshalMessageJSON renders a single Message as indented JSON.
+func MarshalMessageJSON(m *Message) ([]byte, error) {
+	return json.MarshalIndent(NewMessageModel(m), "", "  ")
+}
+
+// MarshalMessageYAML renders a single Message as YAML.
+func MarshalMessageYAML(m *Message) ([]byte, error) {
+	return yaml.Marshal(NewMessageModel(m))
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func testTreeServices() []*Service {
+	return []*Service{
+		{
+			Name: "Greeter",
+			Rpcs: []*Rpc{
+				{
+					Name:             "SayHello",
+					InputParameters:  []*Parameter{{Type: "HelloRequest"}},
+					ReturnParameters: []*Parameter{{Streaming: true, Type: "HelloReply"}},
+					Options: []*RpcOption{
+						{Name: "google.api.http", RawBody: `{get: "/v1/hello"}`, Value: ParseOptionValue(`{get: "/v1/hello"}`)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMarshalTreeJSONStableFieldOrder(t *testing.T) {
+	out, err := MarshalTreeJSON(testTreeServices(), nil)
+	if err != nil {
+		t.Fatalf("MarshalTreeJSON returned error: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("MarshalTreeJSON produced invalid JSON: %v", err)
+	}
+	if _, ok := got["services"]; !ok {
+		t.Errorf(`missing top-level "services" key in %s`, out)
+	}
+	if _, ok := got["messages"]; !ok {
+		t.Errorf(`missing top-level "messages" key in %s`, out)
+	}
+
+	// Marshaling twice must produce byte-identical output: field order comes
+	// from the struct field declarations, not map iteration, so there's
+	// nothing to race.
+	again, err := MarshalTreeJSON(testTreeServices(), nil)
+	if err != nil {
+		t.Fatalf("second MarshalTreeJSON returned error: %v", err)
+	}
+	if string(out) != string(again) {
+		t.Error("MarshalTreeJSON is not stable across repeated calls")
+	}
+}
+
+func TestMarshalTreeYAML(t *testing.T) {
+	out, err := MarshalTreeYAML(testTreeServices(), nil)
+	if err != nil {
+		t.Fatalf("MarshalTreeYAML returned error: %v", err)
+	}
+	if len(out) == 0 {
+		t.Error("MarshalTreeYAML returned empty output")
+	}
+}
+
+func TestRpcOptionModelIncludesStructuredValue(t *testing.T) {
+	opt := &RpcOption{
+		Name:    "google.api.method_signature",
+		RawBody: `"name"`,
+		Value:   ParseOptionValue(`"name"`),
+	}
+
+	model := newRpcOptionModel(opt)
+	if model.Body != `"name"` {
+		t.Errorf("Body = %q, want the back-compat RawBody", model.Body)
+	}
+	if model.Value.Kind != "string" || model.Value.Scalar != "name" {
+		t.Errorf("Value = %+v, want a structured string scalar", model.Value)
+	}
+}
+
+func TestNewOptionValueModelNestedMessage(t *testing.T) {
+	value := ParseOptionValue(`{get: "/v1/hello" additional_bindings {post: "/v1/hi"}}`)
+	model := newOptionValueModel(value)
+
+	if model.Kind != "message" || len(model.Fields) != 2 {
+		t.Fatalf("newOptionValueModel() = %+v, want a 2-field message", model)
+	}
+	if model.Fields[0].Key != "get" || model.Fields[0].Value.Kind != "string" {
+		t.Errorf("unexpected get field: %+v", model.Fields[0])
+	}
+	nested := model.Fields[1]
+	if nested.Key != "additional_bindings" || nested.Value.Kind != "message" {
+		t.Errorf("unexpected additional_bindings field: %+v", nested)
+	}
+}
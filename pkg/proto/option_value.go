@@ -0,0 +1,252 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import "strings"
+
+// OptionValueKind identifies which of the protobuf text-format value shapes
+// an OptionValue holds.
+type OptionValueKind int
+
+const (
+	ScalarString OptionValueKind = iota
+	ScalarNumber
+	ScalarBool
+	ScalarIdent
+	ListValue
+	MessageValue
+)
+
+// OptionValue is one node of the parsed option-body AST: a scalar
+// (string/number/bool/identifier), a `[a, b]` list, or a `{ key: value }`
+// message literal.
+type OptionValue struct {
+	Kind     OptionValueKind
+	Scalar   string
+	Elements []OptionValue
+	Fields   []OptionField
+}
+
+// OptionField is one `key: value` (or `key { ... }`) entry of a message
+// literal. Fields are kept as an ordered slice, not a map, since the same
+// key can legally repeat (e.g. multiple `additional_bindings` entries).
+type OptionField struct {
+	Key   string
+	Value OptionValue
+}
+
+// ParseOptionValue parses the text-format body of a custom option (the part
+// after `=`) into an OptionValue tree. It tolerates the multi-line, nested
+// brace bodies the RPC option loop already joins into a single string, plus
+// line comments and quoted strings containing punctuation. An empty or
+// unparsable body yields a zero-value OptionValue.
+func ParseOptionValue(body string) OptionValue {
+	p := &optionParser{tokens: tokenizeOptionBody(body)}
+	return p.parseValue()
+}
+
+type optionTokenKind int
+
+const (
+	tokIdent optionTokenKind = iota
+	tokString
+	tokNumber
+	tokPunct
+	tokEOF
+)
+
+type optionToken struct {
+	kind optionTokenKind
+	text string
+}
+
+// tokenizeOptionBody turns raw option-body text into a token stream,
+// following the protobuf text-format grammar used for custom options:
+// quoted strings (with `\"` escapes), `//` line comments, the punctuation
+// `{ } [ ] : , ;`, numbers, and bare identifiers.
+func tokenizeOptionBody(s string) []optionToken {
+	var toks []optionToken
+	i, n := 0, len(s)
+	for i < n {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && i+1 < n && s[i+1] == '/':
+			for i < n && s[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			var sb strings.Builder
+			j := i + 1
+			for j < n && s[j] != '"' {
+				if s[j] == '\\' && j+1 < n {
+					sb.WriteByte(unescapeByte(s[j+1]))
+					j += 2
+					continue
+				}
+				sb.WriteByte(s[j])
+				j++
+			}
+			toks = append(toks, optionToken{kind: tokString, text: sb.String()})
+			i = j + 1
+		case strings.IndexByte("{}[]:,;", c) >= 0:
+			toks = append(toks, optionToken{kind: tokPunct, text: string(c)})
+			i++
+		case c == '-' || isDigit(c):
+			j := i + 1
+			for j < n && (isDigit(s[j]) || s[j] == '.' || s[j] == 'e' || s[j] == 'E' || s[j] == '+' || s[j] == '-') {
+				j++
+			}
+			toks = append(toks, optionToken{kind: tokNumber, text: s[i:j]})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < n && isIdentPart(s[j]) {
+				j++
+			}
+			toks = append(toks, optionToken{kind: tokIdent, text: s[i:j]})
+			i = j
+		default:
+			i++
+		}
+	}
+	return toks
+}
+
+// unescapeByte decodes the character following a backslash inside a quoted
+// option string, per the usual `\n`/`\t`/`\r` text-format escapes; anything
+// else (`\"`, `\\`, ...) is passed through literally.
+func unescapeByte(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	default:
+		return c
+	}
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
+
+type optionParser struct {
+	tokens []optionToken
+	pos    int
+}
+
+func (p *optionParser) peek() optionToken {
+	if p.pos >= len(p.tokens) {
+		return optionToken{kind: tokEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *optionParser) next() optionToken {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *optionParser) consumePunct(text string) bool {
+	if t := p.peek(); t.kind == tokPunct && t.text == text {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *optionParser) parseValue() OptionValue {
+	switch t := p.peek(); {
+	case t.kind == tokPunct && t.text == "{":
+		return p.parseMessage()
+	case t.kind == tokPunct && t.text == "[":
+		return p.parseList()
+	default:
+		return p.parseScalar()
+	}
+}
+
+func (p *optionParser) parseMessage() OptionValue {
+	p.next() // consume '{'
+	var fields []OptionField
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || (t.kind == tokPunct && t.text == "}") {
+			break
+		}
+		key := p.next().text
+		p.consumePunct(":")
+		fields = append(fields, OptionField{Key: key, Value: p.parseValue()})
+		if !p.consumePunct(",") {
+			p.consumePunct(";")
+		}
+	}
+	p.consumePunct("}")
+	return OptionValue{Kind: MessageValue, Fields: fields}
+}
+
+func (p *optionParser) parseList() OptionValue {
+	p.next() // consume '['
+	var elems []OptionValue
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || (t.kind == tokPunct && t.text == "]") {
+			break
+		}
+		elems = append(elems, p.parseValue())
+		p.consumePunct(",")
+	}
+	p.consumePunct("]")
+	return OptionValue{Kind: ListValue, Elements: elems}
+}
+
+func (p *optionParser) parseScalar() OptionValue {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		// Adjacent string literals ("a" "b") concatenate, per text-format.
+		text := t.text
+		for p.peek().kind == tokString {
+			text += p.next().text
+		}
+		return OptionValue{Kind: ScalarString, Scalar: text}
+	case tokNumber:
+		return OptionValue{Kind: ScalarNumber, Scalar: t.text}
+	case tokIdent:
+		if t.text == "true" || t.text == "false" {
+			return OptionValue{Kind: ScalarBool, Scalar: t.text}
+		}
+		return OptionValue{Kind: ScalarIdent, Scalar: t.text}
+	default:
+		// No token to parse (empty or malformed body): the documented
+		// zero-value OptionValue.
+		return OptionValue{}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOptionValueScalars(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want OptionValue
+	}{
+		{"string", `"hello"`, OptionValue{Kind: ScalarString, Scalar: "hello"}},
+		{"adjacent strings concatenate", `"foo" "bar"`, OptionValue{Kind: ScalarString, Scalar: "foobar"}},
+		{"escaped newline", `"a\nb"`, OptionValue{Kind: ScalarString, Scalar: "a\nb"}},
+		{"escaped quote", `"a\"b"`, OptionValue{Kind: ScalarString, Scalar: `a"b`}},
+		{"number", `42`, OptionValue{Kind: ScalarNumber, Scalar: "42"}},
+		{"negative float", `-3.5`, OptionValue{Kind: ScalarNumber, Scalar: "-3.5"}},
+		{"bool", `true`, OptionValue{Kind: ScalarBool, Scalar: "true"}},
+		{"identifier", `SOME_ENUM`, OptionValue{Kind: ScalarIdent, Scalar: "SOME_ENUM"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseOptionValue(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOptionValue(%q) = %+v, want %+v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOptionValueList(t *testing.T) {
+	got := ParseOptionValue(`[1, 2, 3]`)
+	want := OptionValue{Kind: ListValue, Elements: []OptionValue{
+		{Kind: ScalarNumber, Scalar: "1"},
+		{Kind: ScalarNumber, Scalar: "2"},
+		{Kind: ScalarNumber, Scalar: "3"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOptionValue(list) = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseOptionValueNestedMessage(t *testing.T) {
+	// Lines joined with "\n", the way the RPC option loop hands multi-line
+	// bodies to the parser, so tokens from adjacent lines stay separate.
+	body := "{\nget: \"/v1/{name=projects/*}\"\nadditional_bindings {\npost: \"/v1/projects:create\"\nbody: \"*\"\n}\n}"
+
+	got := ParseOptionValue(body)
+	if got.Kind != MessageValue {
+		t.Fatalf("got Kind = %v, want MessageValue", got.Kind)
+	}
+	if len(got.Fields) != 2 {
+		t.Fatalf("got %d fields, want 2: %+v", len(got.Fields), got.Fields)
+	}
+
+	get := got.Fields[0]
+	if get.Key != "get" || get.Value.Scalar != "/v1/{name=projects/*}" {
+		t.Errorf("unexpected get field: %+v", get)
+	}
+
+	binding := got.Fields[1]
+	if binding.Key != "additional_bindings" || binding.Value.Kind != MessageValue {
+		t.Fatalf("unexpected additional_bindings field: %+v", binding)
+	}
+	if len(binding.Value.Fields) != 2 {
+		t.Errorf("got %d nested fields, want 2: %+v", len(binding.Value.Fields), binding.Value.Fields)
+	}
+}
+
+func TestParseOptionValueAdjacentScalarFields(t *testing.T) {
+	// Two bare scalar fields joined by a bare newline (no trailing
+	// separator on the first), as the RPC option loop's newline join
+	// produces for the lines "flag1: true" and "flag2: false".
+	got := ParseOptionValue("{flag1: true\nflag2: false}")
+	if got.Kind != MessageValue || len(got.Fields) != 2 {
+		t.Fatalf("ParseOptionValue = %+v, want a 2-field message", got)
+	}
+	if got.Fields[0].Key != "flag1" || got.Fields[0].Value.Scalar != "true" {
+		t.Errorf("unexpected first field: %+v", got.Fields[0])
+	}
+	if got.Fields[1].Key != "flag2" || got.Fields[1].Value.Scalar != "false" {
+		t.Errorf("unexpected second field: %+v", got.Fields[1])
+	}
+}
+
+func TestParseOptionValueEmptyBodyYieldsZeroValue(t *testing.T) {
+	for _, body := range []string{"", "   ", "// only a comment\n"} {
+		if got := ParseOptionValue(body); !reflect.DeepEqual(got, OptionValue{}) {
+			t.Errorf("ParseOptionValue(%q) = %+v, want the zero value", body, got)
+		}
+	}
+}
+
+func TestTokenizeOptionBodySkipsComments(t *testing.T) {
+	toks := tokenizeOptionBody("true // trailing comment\n")
+	if len(toks) != 1 || toks[0].text != "true" {
+		t.Errorf("tokenizeOptionBody = %+v, want a single true token", toks)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseFiles parses each named .proto file and returns the services and
+// messages discovered across all of them, in encounter order.
+func ParseFiles(paths []string) ([]*Service, []*Message, error) {
+	var services []*Service
+	var messages []*Message
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		namespace := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		s, m, err := parseFile(f, namespace)
+		f.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		services = append(services, s...)
+		messages = append(messages, m...)
+	}
+	return services, messages, nil
+}
+
+// parseFile walks one .proto file's lines, dispatching each to whichever
+// top-level visitor (service or message) recognizes it.
+func parseFile(f *os.File, namespace string) ([]*Service, []*Message, error) {
+	scanner := NewScanner(f)
+	serviceVisitor := NewServiceVisitor()
+	messageVisitor := NewMessageVisitor()
+
+	var services []*Service
+	var messages []*Message
+	for scanner.Scan() {
+		line := scanner.ReadLine()
+		switch {
+		case serviceVisitor.CanVisit(line):
+			if s, ok := serviceVisitor.Visit(scanner, line, namespace).(*Service); ok {
+				services = append(services, s)
+			}
+		case messageVisitor.CanVisit(line):
+			if m, ok := messageVisitor.Visit(scanner, line, namespace).(*Message); ok {
+				messages = append(messages, m)
+			}
+		}
+	}
+	return services, messages, nil
+}
+
+// RenderMarkdown renders the full parse tree as Markdown: each service's
+// RPCs, followed by its google.api.http route table and sequence diagrams
+// (via RenderServiceMarkdown), then the message list.
+func RenderMarkdown(services []*Service, messages []*Message) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteString("# Services\n\n")
+	for _, s := range services {
+		fmt.Fprintf(&b, "## %s\n\n", s.Name)
+		for _, r := range s.Rpcs {
+			fmt.Fprintf(&b, "- `%s`\n", r.Name)
+		}
+		b.WriteString("\n")
+		if section := RenderServiceMarkdown(s); section != "" {
+			b.WriteString(section)
+			b.WriteString("\n")
+		}
+	}
+
+	if len(messages) > 0 {
+		b.WriteString("# Messages\n\n")
+		for _, m := range messages {
+			fmt.Fprintf(&b, "- `%s`\n", m.Name)
+		}
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2023 Google LLC
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proto
+
+// RpcOption is a parsed `option (name) = { ... };` declaration attached to
+// an Rpc. Value holds the structured parse of the option body; RawBody is
+// kept alongside it for callers that still want the original text.
+type RpcOption struct {
+	FQN     string
+	Name    string
+	Value   OptionValue
+	RawBody string
+}
+
+// NewRpcOption builds an RpcOption, parsing rawBody into a structured Value
+// tree while keeping rawBody itself for back-compat.
+func NewRpcOption(fqn, name, _, rawBody string) *RpcOption {
+	return &RpcOption{
+		FQN:     fqn,
+		Name:    name,
+		Value:   ParseOptionValue(rawBody),
+		RawBody: rawBody,
+	}
+}
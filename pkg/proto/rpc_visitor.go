@@ -95,44 +95,33 @@ func (rv *RpcVisitor) Visit(scanner Scanner, in *Line, namespace string) interfa
 				}
 			}
 
-			// Process multi-line options
-			for scanner.Scan() {
+			// The first line may already have closed the option (e.g. a
+			// single-line `option (...) = "x";`). Only scan further lines
+			// while it's still open, so a fully-terminated option never
+			// consumes the line after it - which could be this RPC's own
+			// closing brace or its next sibling's declaration.
+			complete := line.Token == Semicolon && braceCount == 0
+
+			for !complete && scanner.Scan() {
 				oBody := scanner.ReadLine()
 
-				// If we hit a new RPC or other non-option content, stop processing
-				if strings.HasPrefix(strings.TrimSpace(oBody.Syntax), "rpc") ||
-					strings.HasPrefix(strings.TrimSpace(oBody.Syntax), "message") ||
-					strings.HasPrefix(strings.TrimSpace(oBody.Syntax), "service") {
-					// Add the current option
-					if len(strings.TrimSpace(optionBody)) > 0 {
-						out.AddRpcOption(NewRpcOption(
-							Join(Period, namespace, out.Name),
-							optionName,
-							"",
-							optionBody))
-					}
-					// Let the service visitor handle the next RPC
-					rv := NewRpcVisitor()
-					if rv.CanVisit(oBody) {
-						rv.Visit(scanner, oBody, namespace)
-					}
-					return out
-				}
-
-				// Check if this line is just a semicolon
+				// A line that's just a semicolon ends the option without
+				// being part of its body.
 				if strings.TrimSpace(oBody.Syntax) == ";" {
+					complete = true
 					break
 				}
 
-				optionBody += oBody.Syntax
+				optionBody += "\n" + oBody.Syntax
 
 				// Update brace count
 				braceCount += strings.Count(oBody.Syntax, "{")
 				braceCount -= strings.Count(oBody.Syntax, "}")
 
-				// Break if we've reached the end of the option
+				// The option ends once its braces balance and we've seen
+				// its terminating semicolon.
 				if oBody.Token == Semicolon && braceCount == 0 {
-					break
+					complete = true
 				}
 			}
 			if len(strings.TrimSpace(optionBody)) > 0 {